@@ -0,0 +1,351 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cve5json adapts records in the MITRE CVE Record Format 5.0
+// (https://github.com/CVEProject/cve-schema) to the nvdcommon.CVEItem and
+// nvdcommon.LogicalTest interfaces, so that cvefeed's matcher can be pointed
+// at either the NVD JSON 1.0 feed (see nvdjson) or CVE 5.0 records without
+// caring which one it's looking at.
+package cve5json
+
+import (
+	"github.com/facebookincubator/nvdtools/cvefeed/nvdcommon"
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+type cveItem struct {
+	record      *CVERecord
+	configNodes []nvdcommon.LogicalTest
+}
+
+// node is a leaf LogicalTest built from a single containers.cna.affected[] entry.
+type node struct {
+	affected *Affected
+	wfnCPEs  []*wfn.Attributes
+	negate   bool
+}
+
+// NewCVEItem adapts a CVE Record Format 5.0 record into nvdcommon.CVEItem,
+// the same way nvdjson.NewDecoder adapts the NVD JSON 1.0 feed.
+func NewCVEItem(record *CVERecord) nvdcommon.CVEItem {
+	item := &cveItem{record: record}
+	for i := range item.record.Containers.CNA.Affected {
+		item.configNodes = append(item.configNodes, newNode(&item.record.Containers.CNA.Affected[i]))
+	}
+	return item
+}
+
+// newNode builds the LogicalTest for one affected entry. defaultStatus
+// "affected" means the listed versions are the exceptions (unaffected), so
+// the resulting match is negated.
+func newNode(affected *Affected) nvdcommon.LogicalTest {
+	return &node{
+		affected: affected,
+		wfnCPEs:  affectedCPEs(affected),
+		negate:   affected.DefaultStatus == "affected",
+	}
+}
+
+// CVEID returns the identifier of the vulnerability (e.g. CVE).
+func (i *cveItem) CVEID() string {
+	if i == nil {
+		return ""
+	}
+	return i.record.CVEMetadata.CVEID
+}
+
+// Config returns a set of tests that identify vulnerable platform.
+func (i *cveItem) Config() []nvdcommon.LogicalTest {
+	if i == nil {
+		return nil
+	}
+	return i.configNodes
+}
+
+// ProblemTypes returns weakness types associated with vulnerability (e.g. CWE)
+func (i *cveItem) ProblemTypes() []string {
+	var cwes []string
+	for _, pt := range i.record.Containers.CNA.ProblemTypes {
+		for _, d := range pt.Descriptions {
+			if d.CWEID != "" {
+				cwes = append(cwes, d.CWEID)
+			} else if d.Description != "" {
+				cwes = append(cwes, d.Description)
+			}
+		}
+	}
+	return cwes
+}
+
+// Description returns English written description of vulnerability
+func (i *cveItem) Description() string {
+	return getLangStr(i.record.Containers.CNA.Descriptions)
+}
+
+// CVSS20base returns CVSS 2.0 base score of vulnerability
+func (i *cveItem) CVSS20base() float64 {
+	for _, m := range i.record.Containers.CNA.Metrics {
+		if m.CVSSV2_0 != nil {
+			return m.CVSSV2_0.BaseScore
+		}
+	}
+	return 0.0
+}
+
+// CVSS30base returns CVSS 3.0/3.1 base score of vulnerability
+func (i *cveItem) CVSS30base() float64 {
+	for _, m := range i.record.Containers.CNA.Metrics {
+		if m.CVSSV3_1 != nil {
+			return m.CVSSV3_1.BaseScore
+		}
+		if m.CVSSV3_0 != nil {
+			return m.CVSSV3_0.BaseScore
+		}
+	}
+	return 0.0
+}
+
+// CVSSMetrics returns every CVSS scoring available for the vulnerability.
+// CVE Record Format 5.0 metrics don't name their source explicitly the way
+// the NVD API 2.0 does, so Source is left as the empty string; callers that
+// need provenance should consult the record's CNA directly.
+func (i *cveItem) CVSSMetrics() []nvdcommon.CVSSMetric {
+	var metrics []nvdcommon.CVSSMetric
+	for _, m := range i.record.Containers.CNA.Metrics {
+		switch {
+		case m.CVSSV3_1 != nil:
+			metrics = append(metrics, cvssMetric("3.1", m.CVSSV3_1))
+		case m.CVSSV3_0 != nil:
+			metrics = append(metrics, cvssMetric("3.0", m.CVSSV3_0))
+		case m.CVSSV2_0 != nil:
+			metrics = append(metrics, cvssMetric("2.0", m.CVSSV2_0))
+		}
+	}
+	return metrics
+}
+
+func cvssMetric(version string, c *CVSS) nvdcommon.CVSSMetric {
+	return nvdcommon.CVSSMetric{
+		Version:      version,
+		Type:         "Primary",
+		VectorString: c.VectorString,
+		BaseScore:    c.BaseScore,
+		Severity:     c.BaseSeverity,
+	}
+}
+
+// PrimaryCVSS returns the metric with the highest CVSS version, matching
+// nvdjson's PrimaryCVSS: newer scoring schemes are considered more
+// authoritative than older ones carried alongside them for compatibility.
+func (i *cveItem) PrimaryCVSS() (nvdcommon.CVSSMetric, bool) {
+	metrics := i.CVSSMetrics()
+	if len(metrics) == 0 {
+		return nvdcommon.CVSSMetric{}, false
+	}
+	best := metrics[0]
+	for _, m := range metrics[1:] {
+		if m.Version > best.Version {
+			best = m
+		}
+	}
+	return best, true
+}
+
+// LogicalOperator implements part of nvdcommon.LogicalTest. An affected
+// entry is a leaf: any one of its version ranges matching is enough.
+func (n *node) LogicalOperator() string {
+	return "OR"
+}
+
+// NegateIfNeeded implements part of nvdcommon.LogicalTest.
+func (n *node) NegateIfNeeded(b bool) bool {
+	if n == nil || !n.negate {
+		return b
+	}
+	return !b
+}
+
+// InnerTests implements part of nvdcommon.LogicalTest. Affected entries are
+// always leaves in this adapter.
+func (n *node) InnerTests() []nvdcommon.LogicalTest {
+	return nil
+}
+
+// CPEs implements part of nvdcommon.LogicalTest.
+func (n *node) CPEs() []*wfn.Attributes {
+	if n == nil {
+		return nil
+	}
+	return n.wfnCPEs
+}
+
+// CPERanges implements part of nvdcommon.LogicalTest. CVE Record Format 5.0
+// versions[] entries are a list of discrete statuses rather than a single
+// start/end pair, so only the first entry matching this node's status -
+// "affected" normally, or the "unaffected" exception when negate is set, the
+// same statuses MatchPlatform looks for - is represented here; entries with
+// several disjoint ranges report just that first one. A version with no
+// lessThan/lessThanOrEqual bound is a single affected point, not an
+// open-ended range, so it's reported with matching start and end.
+func (n *node) CPERanges() []nvdcommon.CPERange {
+	if n == nil || len(n.affected.Versions) == 0 {
+		return nil
+	}
+	wantStatus := "affected"
+	if n.negate {
+		wantStatus = "unaffected"
+	}
+	var r nvdcommon.CPERange
+	found := false
+	for _, v := range n.affected.Versions {
+		status := v.Status
+		if status == "" {
+			status = n.affected.DefaultStatus
+		}
+		if status == "" {
+			status = "affected"
+		}
+		if status != wantStatus {
+			continue
+		}
+		r = nvdcommon.CPERange{VersionStartIncluding: v.Version}
+		switch {
+		case v.LessThan != "":
+			r.VersionEndExcluding = v.LessThan
+		case v.LessThanOrEqual != "":
+			r.VersionEndIncluding = v.LessThanOrEqual
+		default:
+			r.VersionEndIncluding = v.Version
+		}
+		found = true
+		break
+	}
+	if !found {
+		return nil
+	}
+	ranges := make([]nvdcommon.CPERange, len(n.wfnCPEs))
+	for i, cpe := range n.wfnCPEs {
+		ranges[i] = r
+		ranges[i].CPE = cpe
+	}
+	return ranges
+}
+
+// MatchPlatform implements part of nvdcommon.LogicalTest.
+func (n *node) MatchPlatform(platform *wfn.Attributes, requireVersion bool) bool {
+	if n == nil {
+		return false
+	}
+	for _, cpe := range n.wfnCPEs {
+		if !wfn.Match(cpe, platform) {
+			continue
+		}
+		if len(n.affected.Versions) == 0 {
+			if !requireVersion {
+				return true
+			}
+			continue
+		}
+		if platform.Version == wfn.Any || platform.Version == wfn.NA {
+			return true
+		}
+		ver := wfn.StripSlashes(platform.Version)
+		// When negate is set, DefaultStatus is "affected" and the versions[]
+		// entries are the "unaffected" exceptions to it, so it's those -
+		// not "affected" entries - that MatchPlatform must look for here;
+		// NegateIfNeeded flips whatever this returns, so finding an
+		// exception must report true (to be flipped to "not vulnerable").
+		wantStatus := "affected"
+		if n.negate {
+			wantStatus = "unaffected"
+		}
+		for _, v := range n.affected.Versions {
+			status := v.Status
+			if status == "" {
+				status = n.affected.DefaultStatus
+			}
+			if status == "" {
+				status = "affected"
+			}
+			if status != wantStatus {
+				continue
+			}
+			if versionInRange(ver, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// versionInRange reports whether ver falls within the range described by v,
+// using the comparator appropriate for v.VersionType.
+func versionInRange(ver string, v Version) bool {
+	if v.LessThan == "" && v.LessThanOrEqual == "" {
+		return compareVersions(v.VersionType, ver, v.Version) == 0
+	}
+	if compareVersions(v.VersionType, ver, v.Version) < 0 {
+		return false
+	}
+	if v.LessThan != "" {
+		return compareVersions(v.VersionType, ver, v.LessThan) < 0
+	}
+	return compareVersions(v.VersionType, ver, v.LessThanOrEqual) <= 0
+}
+
+// affectedCPEs synthesizes the WFN CPEs an affected entry matches: the
+// explicit cpes[], if any, plus one synthesized from vendor/product or
+// packageName.
+func affectedCPEs(affected *Affected) []*wfn.Attributes {
+	var cpes []*wfn.Attributes
+	for _, uri := range affected.CPEs {
+		if cpe, err := wfn.Parse(uri); err == nil {
+			cpes = append(cpes, cpe)
+		}
+	}
+	product := affected.Product
+	if product == "" {
+		product = affected.PackageName
+	}
+	if product != "" {
+		cpe := wfn.Attributes{
+			Part:    "a",
+			Vendor:  attr(affected.Vendor),
+			Product: attr(product),
+			Version: wfn.Any,
+		}
+		cpes = append(cpes, &cpe)
+	}
+	return cpes
+}
+
+// attr returns s, or wfn.Any if s is empty, so that an unset vendor/product
+// doesn't accidentally become a literal match requirement.
+func attr(s string) string {
+	if s == "" {
+		return wfn.Any
+	}
+	return s
+}
+
+func getLangStr(lss []LangString) string {
+	var s string
+	for _, ls := range lss {
+		s = ls.Value
+		if ls.Lang == "en" {
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cve5json
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+func TestNewCVEItemMatchPlatform(t *testing.T) {
+	record := &CVERecord{
+		CVEMetadata: CVEMetadata{CVEID: "CVE-2026-0001"},
+		Containers: Containers{
+			CNA: CNAContainer{
+				Affected: []Affected{
+					{
+						Vendor:  "acme",
+						Product: "widget",
+						Versions: []Version{
+							{Version: "0", LessThan: "2.0.0", Status: "affected", VersionType: "semver"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	item := NewCVEItem(record)
+	if item.CVEID() != "CVE-2026-0001" {
+		t.Fatalf("CVEID() = %q, want CVE-2026-0001", item.CVEID())
+	}
+
+	vulnerable := &wfn.Attributes{Part: "a", Vendor: "acme", Product: "widget", Version: "1.5.0"}
+	fixed := &wfn.Attributes{Part: "a", Vendor: "acme", Product: "widget", Version: "2.0.0"}
+
+	config := item.Config()
+	if len(config) != 1 {
+		t.Fatalf("Config() returned %d nodes, want 1", len(config))
+	}
+	if !config[0].MatchPlatform(vulnerable, true) {
+		t.Errorf("MatchPlatform(%v) = false, want true", vulnerable.Version)
+	}
+	if config[0].MatchPlatform(fixed, true) {
+		t.Errorf("MatchPlatform(%v) = true, want false", fixed.Version)
+	}
+}
+
+func TestNewCVEItemMatchPlatformDefaultAffectedWithExceptions(t *testing.T) {
+	record := &CVERecord{
+		CVEMetadata: CVEMetadata{CVEID: "CVE-2026-0002"},
+		Containers: Containers{
+			CNA: CNAContainer{
+				Affected: []Affected{
+					{
+						Vendor:        "acme",
+						Product:       "widget",
+						DefaultStatus: "affected",
+						Versions: []Version{
+							{Version: "2.1.0", Status: "unaffected", VersionType: "semver"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	item := NewCVEItem(record)
+	config := item.Config()
+
+	unaffected := &wfn.Attributes{Part: "a", Vendor: "acme", Product: "widget", Version: "2.1.0"}
+	vulnerable := &wfn.Attributes{Part: "a", Vendor: "acme", Product: "widget", Version: "3.0.0"}
+
+	// A LogicalTest's Negate only takes effect once the caller applies
+	// NegateIfNeeded to the raw MatchPlatform result, as the matching
+	// engine does.
+	leaf := config[0]
+	if leaf.NegateIfNeeded(leaf.MatchPlatform(unaffected, true)) {
+		t.Errorf("match(%v) = true, want false (listed as unaffected)", unaffected.Version)
+	}
+	if !leaf.NegateIfNeeded(leaf.MatchPlatform(vulnerable, true)) {
+		t.Errorf("match(%v) = false, want true (defaultStatus affected)", vulnerable.Version)
+	}
+}
+
+func TestCVEItemPrimaryCVSSPrefersHigherVersion(t *testing.T) {
+	record := &CVERecord{
+		Containers: Containers{
+			CNA: CNAContainer{
+				Metrics: []Metric{
+					// Listed V2.0 before V3.1, so a PrimaryCVSS that just
+					// took metrics[0] would get this one wrong; only the
+					// version comparison picks V3.1.
+					{CVSSV2_0: &CVSS{BaseScore: 7.5, BaseSeverity: "HIGH", VectorString: "AV:N/AC:L/Au:N/C:P/I:P/A:P"}},
+					{CVSSV3_1: &CVSS{BaseScore: 9.8, BaseSeverity: "CRITICAL", VectorString: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}},
+				},
+			},
+		},
+	}
+
+	item := NewCVEItem(record)
+	metrics := item.CVSSMetrics()
+	if len(metrics) != 2 {
+		t.Fatalf("CVSSMetrics() returned %d metrics, want 2", len(metrics))
+	}
+
+	primary, ok := item.PrimaryCVSS()
+	if !ok {
+		t.Fatal("PrimaryCVSS() ok = false, want true")
+	}
+	if primary.Version != "3.1" {
+		t.Errorf("PrimaryCVSS().Version = %q, want 3.1 (the higher of the two)", primary.Version)
+	}
+	if primary.Severity != "CRITICAL" {
+		t.Errorf("PrimaryCVSS().Severity = %q, want CRITICAL", primary.Severity)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cve5json
+
+// CVERecord is the subset of the MITRE CVE Record Format 5.0 container
+// (https://github.com/CVEProject/cve-schema) that is needed to match
+// vulnerable platforms. Only the fields consumed by this package are
+// modeled; unknown fields are ignored by encoding/json.
+type CVERecord struct {
+	CVEMetadata CVEMetadata `json:"cveMetadata"`
+	Containers  Containers  `json:"containers"`
+}
+
+// CVEMetadata carries the identifying information for the record.
+type CVEMetadata struct {
+	CVEID string `json:"cveId"`
+}
+
+// Containers holds the CNA (and, eventually, ADP) container data. Only the
+// CNA container is consumed today.
+type Containers struct {
+	CNA CNAContainer `json:"cna"`
+}
+
+// CNAContainer is the data supplied by the CVE Numbering Authority that
+// assigned the record.
+type CNAContainer struct {
+	Affected     []Affected    `json:"affected"`
+	Descriptions []LangString  `json:"descriptions"`
+	ProblemTypes []ProblemType `json:"problemTypes"`
+	Metrics      []Metric      `json:"metrics"`
+}
+
+// LangString pairs a free text value with the language it's written in.
+type LangString struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+// ProblemType carries the weakness classification(s) for the record, e.g. CWEs.
+type ProblemType struct {
+	Descriptions []ProblemTypeDescription `json:"descriptions"`
+}
+
+// ProblemTypeDescription is one weakness description, optionally tied to a CWE ID.
+type ProblemTypeDescription struct {
+	Lang        string `json:"lang"`
+	Description string `json:"description"`
+	CWEID       string `json:"cweId"`
+}
+
+// Metric is one scoring entry; exactly one of the CVSS fields is populated.
+type Metric struct {
+	CVSSV3_1 *CVSS `json:"cvssV3_1,omitempty"`
+	CVSSV3_0 *CVSS `json:"cvssV3_0,omitempty"`
+	CVSSV2_0 *CVSS `json:"cvssV2_0,omitempty"`
+}
+
+// CVSS is the subset of a CVSS metric object used to surface a base score.
+type CVSS struct {
+	BaseScore    float64 `json:"baseScore"`
+	BaseSeverity string  `json:"baseSeverity"`
+	VectorString string  `json:"vectorString"`
+}
+
+// Affected describes one vendor/product (or package) and the version ranges
+// of it that are vulnerable, relative to DefaultStatus.
+type Affected struct {
+	Vendor        string    `json:"vendor"`
+	Product       string    `json:"product"`
+	PackageName   string    `json:"packageName"`
+	CPEs          []string  `json:"cpes"`
+	DefaultStatus string    `json:"defaultStatus"`
+	Versions      []Version `json:"versions"`
+}
+
+// Version describes a single affected/unaffected version, or a range
+// bounded by LessThan/LessThanOrEqual, using the scheme named by VersionType
+// (e.g. "semver", "git", "rpm", "custom").
+type Version struct {
+	Version         string `json:"version"`
+	Status          string `json:"status"`
+	VersionType     string `json:"versionType"`
+	LessThan        string `json:"lessThan,omitempty"`
+	LessThanOrEqual string `json:"lessThanOrEqual,omitempty"`
+}
@@ -0,0 +1,25 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cve5json
+
+import "github.com/facebookincubator/nvdtools/cvefeed/nvdjson"
+
+// compareVersions compares v1 to v2 using the VersionComparator registered
+// for versionType (e.g. "semver", "rpm"), falling back to nvdjson's
+// smartVerCmp heuristic for versionType values ("git", "custom", unset, ...)
+// that have no dedicated comparator.
+func compareVersions(versionType, v1, v2 string) int {
+	return nvdjson.LookupComparator(versionType).Compare(v1, v2)
+}
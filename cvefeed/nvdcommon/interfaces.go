@@ -0,0 +1,97 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nvdcommon defines the types shared by the various feed adapters
+// (nvdjson, cve5json, ...) so that the matching engine in cvefeed doesn't
+// need to know which feed format a given vulnerability record came from.
+package nvdcommon
+
+import "github.com/facebookincubator/nvdtools/wfn"
+
+// CVEItem describes a parsed vulnerability record, regardless of which feed
+// format (NVD JSON 1.0, CVE Record Format 5.0, ...) produced it.
+type CVEItem interface {
+	// CVEID returns the identifier of the vulnerability (e.g. CVE).
+	CVEID() string
+	// Config returns a set of tests that identify vulnerable platform.
+	Config() []LogicalTest
+	// ProblemTypes returns weakness types associated with vulnerability (e.g. CWE)
+	ProblemTypes() []string
+	// Description returns English written description of vulnerability
+	Description() string
+	// CVSS20base returns CVSS 2.0 base score of vulnerability
+	CVSS20base() float64
+	// CVSS30base returns CVSS 3.0/3.1 base score of vulnerability
+	CVSS30base() float64
+	// CVSSMetrics returns every CVSS scoring available for the vulnerability,
+	// across versions and, for feed formats that carry them, sources.
+	CVSSMetrics() []CVSSMetric
+	// PrimaryCVSS returns the metric this package considers authoritative -
+	// preferring a nvd@nist.gov metric over one from another source, and the
+	// higher CVSS version when both are from the same source. ok is false if
+	// no metric is available at all.
+	PrimaryCVSS() (metric CVSSMetric, ok bool)
+}
+
+// CVSSMetric is one CVSS scoring of a vulnerability.
+type CVSSMetric struct {
+	// Version is the CVSS version the score was computed with, e.g. "2.0", "3.1".
+	Version string
+	// Source identifies who supplied the score, e.g. "nvd@nist.gov". Feed
+	// formats that only ever carry NVD's own score still set this.
+	Source string
+	// Type is "Primary" or "Secondary", mirroring the NVD API 2.0 metrics shape.
+	Type string
+	// VectorString is the full CVSS vector, e.g. "CVSS:3.1/AV:N/AC:L/...".
+	VectorString string
+	// BaseScore is the 0.0-10.0 base score.
+	BaseScore float64
+	// ExploitabilityScore is the CVSS exploitability subscore.
+	ExploitabilityScore float64
+	// ImpactScore is the CVSS impact subscore.
+	ImpactScore float64
+	// Severity is the qualitative severity label, e.g. "HIGH".
+	Severity string
+}
+
+// LogicalTest is a node of the boolean tree used to decide whether a given
+// platform is affected by a vulnerability.
+type LogicalTest interface {
+	// LogicalOperator returns the operator ("AND"/"OR") combining InnerTests.
+	LogicalOperator() string
+	// NegateIfNeeded flips b if this node is negated.
+	NegateIfNeeded(b bool) bool
+	// InnerTests returns the child nodes of the logical tree, or nil for a leaf.
+	InnerTests() []LogicalTest
+	// CPEs returns the CPEs this leaf node tests against.
+	CPEs() []*wfn.Attributes
+	// MatchPlatform reports whether platform satisfies this leaf node.
+	MatchPlatform(platform *wfn.Attributes, requireVersion bool) bool
+	// CPERanges returns, for each CPE in CPEs, the version bounds a matching
+	// platform's version must additionally satisfy. It's the static
+	// counterpart of MatchPlatform's version checks, meant for exporters
+	// (e.g. OSV) that need to describe the affected range rather than just
+	// test a candidate against it.
+	CPERanges() []CPERange
+}
+
+// CPERange pairs a CPE with the version bounds a matching platform's version
+// must fall within. An empty bound means "unbounded on that side".
+type CPERange struct {
+	CPE                   *wfn.Attributes
+	VersionStartIncluding string
+	VersionStartExcluding string
+	VersionEndIncluding   string
+	VersionEndExcluding   string
+}
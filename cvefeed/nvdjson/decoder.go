@@ -0,0 +1,83 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvdjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/facebookincubator/nvdtools/cvefeed/jsonschema"
+	"github.com/facebookincubator/nvdtools/cvefeed/nvdcommon"
+)
+
+// Decoder reads a NVD JSON 1.0 feed one CVE_Items[] element at a time
+// instead of decoding the whole feed into memory up front, which matters on
+// the ~250MB combined NVD yearly feeds. Matching can start on the first item
+// before the rest of the feed has even been read.
+type Decoder struct {
+	dec   *json.Decoder
+	ready bool
+}
+
+// NewDecoder returns a Decoder that reads a NVD JSON 1.0 feed from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next item in the feed. It returns io.EOF once the feed is
+// exhausted.
+func (d *Decoder) Next() (nvdcommon.CVEItem, error) {
+	if !d.ready {
+		if err := d.seekToItems(); err != nil {
+			return nil, err
+		}
+		d.ready = true
+	}
+	if !d.dec.More() {
+		return nil, io.EOF
+	}
+	var item jsonschema.NVDCVEFeedJSON10DefCVEItem
+	if err := d.dec.Decode(&item); err != nil {
+		return nil, err
+	}
+	return newCveItem(&item), nil
+}
+
+// seekToItems advances the underlying token stream past the feed's outer
+// object up to and including the '[' that opens the "CVE_Items" array, so
+// that Next can decode the array one element at a time.
+func (d *Decoder) seekToItems() error {
+	for {
+		tok, err := d.dec.Token()
+		if err == io.EOF {
+			return fmt.Errorf("nvdjson: CVE_Items not found in feed")
+		}
+		if err != nil {
+			return err
+		}
+		if key, ok := tok.(string); ok && key == "CVE_Items" {
+			break
+		}
+	}
+	tok, err := d.dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("nvdjson: expected CVE_Items to be an array, got %v", tok)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvdjson
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+const twoItemFeed = `{
+	"CVE_data_type": "CVE",
+	"CVE_Items": [
+		{"cve": {"CVE_data_meta": {"ID": "CVE-2026-0001"}}},
+		{"cve": {"CVE_data_meta": {"ID": "CVE-2026-0002"}}}
+	]
+}`
+
+func TestDecoderNextReturnsItemsInOrderThenEOF(t *testing.T) {
+	d := NewDecoder(strings.NewReader(twoItemFeed))
+
+	item, err := d.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v, want nil", err)
+	}
+	if got := item.CVEID(); got != "CVE-2026-0001" {
+		t.Errorf("first item CVEID() = %q, want CVE-2026-0001", got)
+	}
+
+	item, err = d.Next()
+	if err != nil {
+		t.Fatalf("Next() error = %v, want nil", err)
+	}
+	if got := item.CVEID(); got != "CVE-2026-0002" {
+		t.Errorf("second item CVEID() = %q, want CVE-2026-0002", got)
+	}
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("Next() error = %v, want io.EOF once CVE_Items is exhausted", err)
+	}
+}
+
+func TestDecoderNextOnEmptyArrayIsImmediateEOF(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"CVE_Items": []}`))
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("Next() error = %v, want io.EOF for an empty CVE_Items", err)
+	}
+}
+
+func TestDecoderNextErrorsWhenCVEItemsMissing(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"CVE_data_type": "CVE"}`))
+	if _, err := d.Next(); err == nil {
+		t.Error("Next() error = nil, want an error when CVE_Items is absent from the feed")
+	}
+}
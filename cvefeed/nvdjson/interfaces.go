@@ -15,8 +15,8 @@
 package nvdjson
 
 import (
-	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/facebookincubator/nvdtools/cvefeed/jsonschema"
 	"github.com/facebookincubator/nvdtools/cvefeed/nvdcommon"
@@ -31,12 +31,31 @@ type cveItem struct {
 type node struct {
 	node              *jsonschema.NVDCVEFeedJSON10DefNode
 	nvdcommonChildren []nvdcommon.LogicalTest
-	wfnCPEs           []*wfn.Attributes
+	cpeMatches        []*cpeMatch
 }
 
+// cpeMatch wraps a single CPEMatch JSON node and lazily parses its CPE URI:
+// MatchPlatform re-checks every node's CPEs against every candidate
+// platform, so eagerly parsing all of them up front (as newNode used to)
+// means parsing CPEs that never end up being consulted on a bulk scan.
 type cpeMatch struct {
 	cpeMatch *jsonschema.NVDCVEFeedJSON10DefCPEMatch
+	once     sync.Once
 	wfname   *wfn.Attributes
+	err      error
+}
+
+// wfnAttributes returns the parsed WFN for this CPE match, parsing and
+// caching it on first use.
+func (m *cpeMatch) wfnAttributes() (*wfn.Attributes, error) {
+	m.once.Do(func() {
+		uri := m.cpeMatch.Cpe23Uri
+		if uri == "" {
+			uri = m.cpeMatch.Cpe22Uri
+		}
+		m.wfname, m.err = wfn.Parse(uri)
+	})
+	return m.wfname, m.err
 }
 
 func newCveItem(json *jsonschema.NVDCVEFeedJSON10DefCVEItem) nvdcommon.CVEItem {
@@ -53,20 +72,16 @@ func newNode(json *jsonschema.NVDCVEFeedJSON10DefNode) nvdcommon.LogicalTest {
 	if len(n.node.Children) != 0 {
 		children := make([]nvdcommon.LogicalTest, len(n.node.Children))
 		for i, child := range n.node.Children {
-			children[i] = nvdcommon.LogicalTest(&node{node: child})
+			children[i] = newNode(child)
 		}
 		n.nvdcommonChildren = children
 	}
 
 	if len(n.node.CPEMatch) != 0 {
-		cpes := make([]*wfn.Attributes, len(n.node.CPEMatch))
-		for i, node := range n.node.CPEMatch {
-			cpe, err := node2CPE(&cpeMatch{cpeMatch: node})
-			if err == nil {
-				cpes[i] = cpe
-			}
+		n.cpeMatches = make([]*cpeMatch, len(n.node.CPEMatch))
+		for i, cm := range n.node.CPEMatch {
+			n.cpeMatches[i] = &cpeMatch{cpeMatch: cm}
 		}
-		n.wfnCPEs = cpes
 	}
 
 	return n
@@ -131,6 +146,51 @@ func (i *cveItem) CVSS30base() float64 {
 	return 0.0
 }
 
+// CVSSMetrics returns every CVSS scoring available for the vulnerability.
+// The NVD JSON 1.0 feed only ever carries one metric per CVSS version, both
+// computed by NVD itself, so Source is always "nvd@nist.gov" and Type "Primary".
+func (i *cveItem) CVSSMetrics() []nvdcommon.CVSSMetric {
+	var metrics []nvdcommon.CVSSMetric
+	if i.cveItem.Impact == nil {
+		return nil
+	}
+	if bm := i.cveItem.Impact.BaseMetricV2; bm != nil && bm.CVSSV2 != nil {
+		metrics = append(metrics, nvdcommon.CVSSMetric{
+			Version:             bm.CVSSV2.Version,
+			Source:              "nvd@nist.gov",
+			Type:                "Primary",
+			VectorString:        bm.CVSSV2.VectorString,
+			BaseScore:           bm.CVSSV2.BaseScore,
+			ExploitabilityScore: bm.ExploitabilityScore,
+			ImpactScore:         bm.ImpactScore,
+			Severity:            bm.Severity,
+		})
+	}
+	if bm := i.cveItem.Impact.BaseMetricV3; bm != nil && bm.CVSSV3 != nil {
+		metrics = append(metrics, nvdcommon.CVSSMetric{
+			Version:             bm.CVSSV3.Version,
+			Source:              "nvd@nist.gov",
+			Type:                "Primary",
+			VectorString:        bm.CVSSV3.VectorString,
+			BaseScore:           bm.CVSSV3.BaseScore,
+			ExploitabilityScore: bm.ExploitabilityScore,
+			ImpactScore:         bm.ImpactScore,
+			Severity:            bm.CVSSV3.BaseSeverity,
+		})
+	}
+	return metrics
+}
+
+// PrimaryCVSS returns the highest CVSS version metric available, since every
+// metric in the NVD JSON 1.0 feed already comes from nvd@nist.gov.
+func (i *cveItem) PrimaryCVSS() (nvdcommon.CVSSMetric, bool) {
+	metrics := i.CVSSMetrics()
+	if len(metrics) == 0 {
+		return nvdcommon.CVSSMetric{}, false
+	}
+	return metrics[len(metrics)-1], true
+}
+
 // LogicalOperator implements part of cvefeed.LogicalTest interface
 func (n *node) LogicalOperator() string {
 	if n == nil {
@@ -160,7 +220,35 @@ func (n *node) CPEs() []*wfn.Attributes {
 	if n == nil {
 		return nil
 	}
-	return n.wfnCPEs
+	cpes := make([]*wfn.Attributes, len(n.cpeMatches))
+	for i, m := range n.cpeMatches {
+		if cpe, err := m.wfnAttributes(); err == nil {
+			cpes[i] = cpe
+		}
+	}
+	return cpes
+}
+
+// CPERanges implements part of cvefeed.LogicalTest interface
+func (n *node) CPERanges() []nvdcommon.CPERange {
+	if n == nil {
+		return nil
+	}
+	ranges := make([]nvdcommon.CPERange, len(n.cpeMatches))
+	for i, m := range n.cpeMatches {
+		cpe, err := m.wfnAttributes()
+		if err != nil {
+			continue
+		}
+		ranges[i] = nvdcommon.CPERange{
+			CPE:                   cpe,
+			VersionStartIncluding: m.cpeMatch.VersionStartIncluding,
+			VersionStartExcluding: m.cpeMatch.VersionStartExcluding,
+			VersionEndIncluding:   m.cpeMatch.VersionEndIncluding,
+			VersionEndExcluding:   m.cpeMatch.VersionEndExcluding,
+		}
+	}
+	return ranges
 }
 
 // MatchPlatform implements part of cvefeed.LogicalTest interface
@@ -168,11 +256,15 @@ func (n *node) MatchPlatform(platform *wfn.Attributes, requireVersion bool) bool
 	if n == nil {
 		return false
 	}
-	for _, cpeNode := range n.node.CPEMatch {
-		cpe, err := node2CPE(&cpeMatch{cpeMatch: cpeNode})
+	for _, m := range n.cpeMatches {
+		parsed, err := m.wfnAttributes()
 		if err != nil {
 			continue
 		}
+		cpeNode := m.cpeMatch
+		// Copy: parsed is cached and shared across calls, but below we may
+		// need to force Version to ANY for this call only.
+		cpe := *parsed
 		// Not sure if this is needed, in the feed whenever there is a version constraints, version attributes is already ANY,
 		// but better safe, than sorry.
 		if cpeNode.VersionStartIncluding != "" || cpeNode.VersionStartExcluding != "" ||
@@ -181,7 +273,7 @@ func (n *node) MatchPlatform(platform *wfn.Attributes, requireVersion bool) bool
 		} else if requireVersion && cpe.Version == wfn.Any {
 			continue
 		}
-		if wfn.Match(cpe, platform) {
+		if wfn.Match(&cpe, platform) {
 			if platform.Version == wfn.Any || platform.Version == wfn.NA {
 				// logical value of N/A only matches logical value of ANY, so technically, this should
 				// return platform.Version == wfn.Any || cpe.Version == wfn.Any
@@ -196,16 +288,17 @@ func (n *node) MatchPlatform(platform *wfn.Attributes, requireVersion bool) bool
 				return false
 			}
 			ver := wfn.StripSlashes(platform.Version)
-			if cpeNode.VersionStartIncluding != "" && smartVerCmp(ver, cpeNode.VersionStartIncluding) < 0 {
+			cmp := versionComparatorFor(&cpe)
+			if cpeNode.VersionStartIncluding != "" && cmp.Compare(ver, cpeNode.VersionStartIncluding) < 0 {
 				continue
 			}
-			if cpeNode.VersionStartExcluding != "" && smartVerCmp(ver, cpeNode.VersionStartExcluding) <= 0 {
+			if cpeNode.VersionStartExcluding != "" && cmp.Compare(ver, cpeNode.VersionStartExcluding) <= 0 {
 				continue
 			}
-			if cpeNode.VersionEndIncluding != "" && smartVerCmp(ver, cpeNode.VersionEndIncluding) > 0 {
+			if cpeNode.VersionEndIncluding != "" && cmp.Compare(ver, cpeNode.VersionEndIncluding) > 0 {
 				continue
 			}
-			if cpeNode.VersionEndExcluding != "" && smartVerCmp(ver, cpeNode.VersionEndExcluding) >= 0 {
+			if cpeNode.VersionEndExcluding != "" && cmp.Compare(ver, cpeNode.VersionEndExcluding) >= 0 {
 				continue
 			}
 			return true
@@ -214,23 +307,9 @@ func (n *node) MatchPlatform(platform *wfn.Attributes, requireVersion bool) bool
 	return false
 }
 
-func node2CPE(node *cpeMatch) (*wfn.Attributes, error) {
-	var err error
-	if node == nil {
-		return nil, fmt.Errorf("cannot collect CPEs from nil node")
-	}
-	if node.wfname != nil {
-		return node.wfname, nil
-	}
-	uri := node.cpeMatch.Cpe23Uri
-	if uri == "" {
-		uri = node.cpeMatch.Cpe22Uri
-	}
-	node.wfname, err = wfn.Parse(uri)
-	return node.wfname, err
-}
-
-// smartVerCmp compares stringified versions of software.
+// smartVerCmp compares stringified versions of software. It's registered in
+// vercmp.go as the default VersionComparator, used for any CPE that doesn't
+// match a more specific one (RPM, dpkg, JVM, semver, ...).
 // It tries to do the right thing for any type of versioning,
 // assuming v1 and v2 have the same version convension.
 // It will return meaningful result for "95SE" vs "98SP1" or for "16.3.2" vs. "3.7.0",
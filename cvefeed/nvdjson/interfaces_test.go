@@ -0,0 +1,53 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvdjson
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/nvdtools/cvefeed/jsonschema"
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+func TestNodeMatchPlatformDoesNotCorruptCachedCPE(t *testing.T) {
+	// The dictionary CPE names a concrete version (1.2), but this node also
+	// carries a start/end range, so MatchPlatform must compare against the
+	// range rather than 1.2 literally - forcing a *copy's* Version to
+	// wfn.Any, without touching the cached parse.
+	n := newNode(&jsonschema.NVDCVEFeedJSON10DefNode{
+		Operator: "OR",
+		CPEMatch: []*jsonschema.NVDCVEFeedJSON10DefCPEMatch{
+			{
+				Cpe23Uri:              "cpe:2.3:a:acme:widget:1.2:*:*:*:*:*:*:*",
+				VersionStartIncluding: "1.0.0",
+				VersionEndExcluding:   "2.0.0",
+			},
+		},
+	})
+
+	vulnerable := &wfn.Attributes{Part: "a", Vendor: "acme", Product: "widget", Version: "1.5.0"}
+	if !n.MatchPlatform(vulnerable, true) {
+		t.Fatalf("MatchPlatform(%v) = false, want true", vulnerable.Version)
+	}
+
+	// If MatchPlatform mutated the shared cached *wfn.Attributes instead of
+	// a local copy, the cache's Version would now read wfn.Any instead of
+	// the dictionary's "1\.2" (WFN form, dot escaped) - corrupting every
+	// later call against this node.
+	cpes := n.CPEs()
+	if len(cpes) != 1 || cpes[0].Version != `1\.2` {
+		t.Errorf("cached CPE Version = %q, want the untouched %q", cpes[0].Version, `1\.2`)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvdjson
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+// VersionComparator compares two version strings drawn from a CPE match
+// range (VersionStartIncluding, VersionEndExcluding, etc). It returns -1 if
+// v1 < v2, 1 if v1 > v2, and 0 if they're equal.
+//
+// smartVerCmp is a reasonable default, but it's a heuristic: it doesn't know
+// about epochs, the Debian "~" pre-release marker, or the JVM's two version
+// schemes. Register a VersionComparator for the target_sw values that need
+// better handling via RegisterVersionComparator.
+type VersionComparator interface {
+	Compare(v1, v2 string) int
+}
+
+// VersionComparatorFunc adapts a plain func to VersionComparator.
+type VersionComparatorFunc func(v1, v2 string) int
+
+// Compare implements VersionComparator.
+func (f VersionComparatorFunc) Compare(v1, v2 string) int { return f(v1, v2) }
+
+var (
+	defaultComparator = VersionComparatorFunc(smartVerCmp)
+
+	comparatorsMu sync.RWMutex
+	// comparators is keyed by lower-cased target_sw.
+	comparators = map[string]VersionComparator{
+		"rhel":    VersionComparatorFunc(rpmVerCmp),
+		"centos":  VersionComparatorFunc(rpmVerCmp),
+		"fedora":  VersionComparatorFunc(rpmVerCmp),
+		"rpm":     VersionComparatorFunc(rpmVerCmp),
+		"debian":  VersionComparatorFunc(dpkgVerCmp),
+		"ubuntu":  VersionComparatorFunc(dpkgVerCmp),
+		"jvm":     VersionComparatorFunc(jvmVerCmp),
+		"java":    VersionComparatorFunc(jvmVerCmp),
+		"jdk":     VersionComparatorFunc(jvmVerCmp),
+		"jre":     VersionComparatorFunc(jvmVerCmp),
+		"python":  VersionComparatorFunc(semverVerCmp),
+		"node.js": VersionComparatorFunc(semverVerCmp),
+		"npm":     VersionComparatorFunc(semverVerCmp),
+		"golang":  VersionComparatorFunc(semverVerCmp),
+		"ruby":    VersionComparatorFunc(semverVerCmp),
+		"semver":  VersionComparatorFunc(semverVerCmp),
+	}
+)
+
+// RegisterVersionComparator makes cmp the VersionComparator used for CPEs
+// whose WFN target_sw equals targetSW (case-insensitive). It overwrites any
+// previously registered comparator for that target_sw.
+func RegisterVersionComparator(targetSW string, cmp VersionComparator) {
+	comparatorsMu.Lock()
+	defer comparatorsMu.Unlock()
+	comparators[strings.ToLower(targetSW)] = cmp
+}
+
+// LookupComparator returns the VersionComparator registered under key
+// (case-insensitive), or the default smartVerCmp-based one if none was
+// registered. key is usually a WFN target_sw, but other feed adapters (e.g.
+// cve5json's versionType) share the same registry.
+func LookupComparator(key string) VersionComparator {
+	comparatorsMu.RLock()
+	defer comparatorsMu.RUnlock()
+	if cmp, ok := comparators[strings.ToLower(key)]; ok {
+		return cmp
+	}
+	return defaultComparator
+}
+
+// versionComparatorFor picks the VersionComparator to use for cpe, based on
+// its target_sw, falling back to vendor/product for ecosystems (RHEL,
+// Debian, ...) that the NVD feed doesn't always encode in target_sw, and
+// finally to the smartVerCmp heuristic.
+func versionComparatorFor(cpe *wfn.Attributes) VersionComparator {
+	comparatorsMu.RLock()
+	defer comparatorsMu.RUnlock()
+
+	if cpe != nil {
+		if cmp, ok := comparators[strings.ToLower(cpe.TargetSW)]; ok {
+			return cmp
+		}
+		for _, field := range []string{cpe.Vendor, cpe.Product} {
+			if cmp, ok := comparators[strings.ToLower(field)]; ok {
+				return cmp
+			}
+		}
+	}
+	return defaultComparator
+}
+
+// semverVerCmp compares v1 and v2 as semantic versions, falling back to
+// smartVerCmp for values blang/semver can't parse (e.g. "1.8.0_202").
+func semverVerCmp(v1, v2 string) int {
+	a, err1 := semver.ParseTolerant(v1)
+	b, err2 := semver.ParseTolerant(v2)
+	if err1 != nil || err2 != nil {
+		return smartVerCmp(v1, v2)
+	}
+	return a.Compare(b)
+}
+
+// rpmVerCmp compares RPM-style "[epoch:]version[-release]" strings using the
+// classic rpmvercmp algorithm: alternating runs of digits and letters are
+// compared segment by segment, numerically and lexically respectively.
+func rpmVerCmp(v1, v2 string) int {
+	e1, r1 := splitEVR(v1)
+	e2, r2 := splitEVR(v2)
+	if c := rpmSegmentCmp(e1, e2); c != 0 {
+		return c
+	}
+	return rpmSegmentCmp(r1, r2)
+}
+
+// splitEVR splits "epoch:version-release" into the "version-release" part
+// compared first by epoch (defaulting to "0") then the rest verbatim.
+func splitEVR(v string) (epoch, rest string) {
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return "0", v
+}
+
+var rpmSegmentRe = regexp.MustCompile(`(\d+|[a-zA-Z]+|[^a-zA-Z\d]+)`)
+
+// rpmSegmentCmp compares two version strings segment by segment, the way
+// rpmvercmp does: digit runs compare numerically, everything else lexically.
+func rpmSegmentCmp(v1, v2 string) int {
+	segs1 := rpmSegmentRe.FindAllString(v1, -1)
+	segs2 := rpmSegmentRe.FindAllString(v2, -1)
+	n := len(segs1)
+	if len(segs2) < n {
+		n = len(segs2)
+	}
+	for i := 0; i < n; i++ {
+		s1, s2 := segs1[i], segs2[i]
+		n1, err1 := strconv.Atoi(s1)
+		n2, err2 := strconv.Atoi(s2)
+		if err1 == nil && err2 == nil {
+			if n1 != n2 {
+				return sign(n1 - n2)
+			}
+			continue
+		}
+		if c := strings.Compare(s1, s2); c != 0 {
+			return sign(c)
+		}
+	}
+	if len(segs1) == len(segs2) {
+		return 0
+	}
+	// One version has extra trailing segments the other lacks. rpmvercmp
+	// treats a dangling numeric segment as newer (e.g. "1.0.1" > "1.0"),
+	// but a dangling alphabetic segment as an unreleased pre-release tag,
+	// so that one is older (e.g. "1.0" > "1.0rc1").
+	// Separator segments (".", "-", ...) are skipped to reach the first
+	// dangling segment that carries a value.
+	lengthDiff := sign(len(segs1) - len(segs2))
+	extra := segs2[n:]
+	if len(segs1) > len(segs2) {
+		extra = segs1[n:]
+	}
+	for _, s := range extra {
+		if c := s[0]; c >= '0' && c <= '9' {
+			return lengthDiff
+		} else if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			return -lengthDiff
+		}
+	}
+	return lengthDiff
+}
+
+// dpkgVerCmp compares Debian package versions, which follow the same
+// epoch:upstream-revision shape as RPM but additionally define "~" as
+// sorting before anything else, including the end of the string - it's how
+// Debian orders pre-releases ("1.0~beta1" < "1.0").
+func dpkgVerCmp(v1, v2 string) int {
+	e1, r1 := splitEVR(v1)
+	e2, r2 := splitEVR(v2)
+	if c := dpkgSegmentCmp(e1, e2); c != 0 {
+		return c
+	}
+	return dpkgSegmentCmp(r1, r2)
+}
+
+func dpkgSegmentCmp(v1, v2 string) int {
+	segs1 := rpmSegmentRe.FindAllString(v1, -1)
+	segs2 := rpmSegmentRe.FindAllString(v2, -1)
+	for i := 0; i < len(segs1) || i < len(segs2); i++ {
+		s1, s2 := segAt(segs1, i), segAt(segs2, i)
+		isTilde1, isTilde2 := s1 == "~", s2 == "~"
+		if isTilde1 && !isTilde2 {
+			return -1
+		}
+		if isTilde2 && !isTilde1 {
+			return 1
+		}
+		n1, err1 := strconv.Atoi(s1)
+		n2, err2 := strconv.Atoi(s2)
+		if err1 == nil && err2 == nil {
+			if n1 != n2 {
+				return sign(n1 - n2)
+			}
+			continue
+		}
+		if c := strings.Compare(s1, s2); c != 0 {
+			return sign(c)
+		}
+	}
+	return 0
+}
+
+// segAt returns segs[i], or "" if i is out of range - dpkgSegmentCmp needs
+// to keep comparing a "~" tail against a missing segment on the other side.
+func segAt(segs []string, i int) string {
+	if i < 0 || i >= len(segs) {
+		return ""
+	}
+	return segs[i]
+}
+
+var (
+	reJVMOld    = regexp.MustCompile(`^1\.(\d+)\.(\d+)(?:_(\d+))?`)
+	reJVMUpdate = regexp.MustCompile(`^(\d+)u(\d+)$`)
+	reJVMNew    = regexp.MustCompile(`^(\d+)(?:\.(\d+)\.(\d+))?`)
+)
+
+// jvmVerCmp compares JVM versions across the three schemes a vendor might
+// report: the legacy "1.8.0_202", the modern "8.0.202", and the update
+// shorthand "8u202". All three are normalized to (major, minor, security)
+// before comparing; anything that parses as none of them falls back to
+// smartVerCmp.
+func jvmVerCmp(v1, v2 string) int {
+	a, ok1 := parseJVMVersion(v1)
+	b, ok2 := parseJVMVersion(v2)
+	if !ok1 || !ok2 {
+		return smartVerCmp(v1, v2)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return sign(a[i] - b[i])
+		}
+	}
+	return 0
+}
+
+// parseJVMVersion normalizes v into a (major, minor, security) triple.
+func parseJVMVersion(v string) (triple [3]int, ok bool) {
+	if m := reJVMUpdate.FindStringSubmatch(v); m != nil {
+		triple[0] = atoi(m[1])
+		triple[2] = atoi(m[2])
+		return triple, true
+	}
+	if m := reJVMOld.FindStringSubmatch(v); m != nil {
+		triple[0] = atoi(m[1])
+		triple[1] = atoi(m[2])
+		triple[2] = atoi(m[3])
+		return triple, true
+	}
+	if m := reJVMNew.FindStringSubmatch(v); m != nil && m[0] != "" {
+		triple[0] = atoi(m[1])
+		triple[1] = atoi(m[2])
+		triple[2] = atoi(m[3])
+		return triple, true
+	}
+	return triple, false
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
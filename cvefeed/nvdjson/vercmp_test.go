@@ -0,0 +1,79 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvdjson
+
+import "testing"
+
+func TestRpmVerCmp(t *testing.T) {
+	cases := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0.1", "1.0", 1},
+		{"1.0", "1.0.1", -1},
+		// A dangling alphabetic segment is a pre-release tag: older, not newer.
+		{"1.0", "1.0a", 1},
+		{"1.0a", "1.0", -1},
+		{"2.3.4", "2.3.4beta2", 1},
+		{"1.5.0rc1", "1.5.0", -1},
+		{"1:1.0", "2.0", 1}, // epoch wins over version
+	}
+	for _, c := range cases {
+		if got := rpmVerCmp(c.v1, c.v2); sign(got) != sign(c.want) {
+			t.Errorf("rpmVerCmp(%q, %q) = %d, want sign %d", c.v1, c.v2, got, c.want)
+		}
+	}
+}
+
+func TestDpkgVerCmp(t *testing.T) {
+	cases := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.0-1", "1.0-2", -1},
+		// "~" sorts before everything, even the end of the string.
+		{"1.0~beta1", "1.0", -1},
+		{"1.0", "1.0~beta1", 1},
+		{"1.0~beta1", "1.0~beta2", -1},
+		{"1:1.0", "2.0", 1}, // epoch wins over version
+	}
+	for _, c := range cases {
+		if got := dpkgVerCmp(c.v1, c.v2); sign(got) != sign(c.want) {
+			t.Errorf("dpkgVerCmp(%q, %q) = %d, want sign %d", c.v1, c.v2, got, c.want)
+		}
+	}
+}
+
+func TestJvmVerCmp(t *testing.T) {
+	cases := []struct {
+		v1, v2 string
+		want   int
+	}{
+		{"8", "8", 0},
+		// All three schemes for the same release should compare as equal.
+		{"1.8.0_202", "8.0.202", 0},
+		{"8u202", "8.0.202", 0},
+		{"1.8.0_202", "8u202", 0},
+		{"1.8.0_201", "1.8.0_202", -1},
+		{"11.0.1", "8.0.202", 1},
+	}
+	for _, c := range cases {
+		if got := jvmVerCmp(c.v1, c.v2); sign(got) != sign(c.want) {
+			t.Errorf("jvmVerCmp(%q, %q) = %d, want sign %d", c.v1, c.v2, got, c.want)
+		}
+	}
+}
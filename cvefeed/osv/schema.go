@@ -0,0 +1,62 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package osv models the subset of the OSV (Open Source Vulnerability)
+// schema 1.x (https://ossf.github.io/osv-schema/) that cvefeed produces.
+package osv
+
+// Vulnerability is a single OSV record.
+type Vulnerability struct {
+	SchemaVersion string     `json:"schema_version"`
+	ID            string     `json:"id"`
+	Aliases       []string   `json:"aliases,omitempty"`
+	Summary       string     `json:"summary,omitempty"`
+	Details       string     `json:"details,omitempty"`
+	Severity      []Severity `json:"severity,omitempty"`
+	Affected      []Affected `json:"affected,omitempty"`
+}
+
+// Severity is one severity scoring of the vulnerability.
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected is one package (or CPE, for formats with no package registry
+// equivalent) and the version ranges of it that are vulnerable.
+type Affected struct {
+	Package           Package                `json:"package"`
+	Ranges            []Range                `json:"ranges,omitempty"`
+	EcosystemSpecific map[string]interface{} `json:"ecosystem_specific,omitempty"`
+}
+
+// Package identifies the affected software.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range is one contiguous span of affected versions.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is one boundary of a Range: exactly one field is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
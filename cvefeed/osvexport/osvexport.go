@@ -0,0 +1,232 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package osvexport converts a parsed nvdcommon.CVEItem into an OSV
+// (https://ossf.github.io/osv-schema/) record, so that a single NVD-to-OSV
+// pass can feed osv-scanner and similar tooling.
+package osvexport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/facebookincubator/nvdtools/cvefeed/nvdcommon"
+	"github.com/facebookincubator/nvdtools/cvefeed/osv"
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+// Convert builds an OSV record from item. Configuration nodes are walked in
+// disjunctive normal form: an OR produces one Affected per branch, an AND
+// produces one Affected per CPE in the group with the group's other CPEs
+// recorded under ecosystem_specific.and_conditions, since OSV has no native
+// way to say "only vulnerable when both A and B are present".
+func Convert(item nvdcommon.CVEItem) osv.Vulnerability {
+	v := osv.Vulnerability{
+		SchemaVersion: "1.6.0",
+		ID:            item.CVEID(),
+		Details:       item.Description(),
+	}
+
+	for _, metric := range item.CVSSMetrics() {
+		if metric.VectorString == "" {
+			continue
+		}
+		major := metric.Version
+		if i := strings.IndexByte(major, '.'); i >= 0 {
+			major = major[:i]
+		}
+		v.Severity = append(v.Severity, osv.Severity{
+			Type:  "CVSS_V" + major,
+			Score: metric.VectorString,
+		})
+	}
+
+	var groups []group
+	for _, top := range item.Config() {
+		groups = append(groups, flatten(top)...)
+	}
+	for _, g := range groups {
+		v.Affected = append(v.Affected, g.affected()...)
+	}
+	return v
+}
+
+// group is a conjunction (AND) of CPERanges that must all hold at once.
+type group []nvdcommon.CPERange
+
+// flatten walks a configuration node into disjunctive normal form: a slice
+// of groups, any one of which being true means the configuration matches.
+// Negated nodes are dropped - "vulnerable unless this matches" has no direct
+// OSV representation, so it's left out rather than guessed at.
+func flatten(t nvdcommon.LogicalTest) []group {
+	if t == nil || t.NegateIfNeeded(false) {
+		return nil
+	}
+	if children := t.InnerTests(); len(children) > 0 {
+		if strings.EqualFold(t.LogicalOperator(), "AND") {
+			groups := []group{{}}
+			for _, c := range children {
+				groups = cartesianMerge(groups, flatten(c))
+			}
+			return groups
+		}
+		var groups []group
+		for _, c := range children {
+			groups = append(groups, flatten(c)...)
+		}
+		return groups
+	}
+	var groups []group
+	for _, r := range t.CPERanges() {
+		if r.CPE == nil {
+			continue
+		}
+		groups = append(groups, group{r})
+	}
+	return groups
+}
+
+// cartesianMerge combines every group in a with every group in b, the way
+// an AND of two OR-branches distributes. If b is empty (e.g. a negated or
+// CPE-less child), a is returned unchanged rather than collapsing to empty.
+func cartesianMerge(a, b []group) []group {
+	if len(b) == 0 {
+		return a
+	}
+	groups := make([]group, 0, len(a)*len(b))
+	for _, ga := range a {
+		for _, gb := range b {
+			merged := make(group, 0, len(ga)+len(gb))
+			merged = append(merged, ga...)
+			merged = append(merged, gb...)
+			groups = append(groups, merged)
+		}
+	}
+	return groups
+}
+
+// affected turns a group into one osv.Affected per CPE in it: the CPE under
+// test, plus (when the group has more than one CPE) the rest recorded as
+// and_conditions.
+func (g group) affected() []osv.Affected {
+	var purls []string
+	for _, r := range g {
+		purls = append(purls, purl(r.CPE))
+	}
+	affected := make([]osv.Affected, 0, len(g))
+	for i, r := range g {
+		a := osv.Affected{
+			Package: pkg(r.CPE),
+			Ranges:  []osv.Range{rangeOf(r)},
+		}
+		if others := otherThan(purls, i); len(others) > 0 {
+			a.EcosystemSpecific = map[string]interface{}{"and_conditions": others}
+		}
+		affected = append(affected, a)
+	}
+	return affected
+}
+
+func otherThan(purls []string, i int) []string {
+	if len(purls) < 2 {
+		return nil
+	}
+	others := make([]string, 0, len(purls)-1)
+	for j, p := range purls {
+		if j != i {
+			others = append(others, p)
+		}
+	}
+	return others
+}
+
+// ecosystem infers the OSV ecosystem name from a CPE's target_sw alone (part
+// is not consulted), falling back to "Generic" for anything that isn't a
+// known registry.
+func ecosystem(cpe *wfn.Attributes) string {
+	switch strings.ToLower(cpe.TargetSW) {
+	case "python", "pypi":
+		return "PyPI"
+	case "node.js", "nodejs", "npm":
+		return "npm"
+	case "golang", "go":
+		return "Go"
+	case "java", "maven":
+		return "Maven"
+	case "ruby", "rubygems", "gem":
+		return "RubyGems"
+	case "rust", "cargo", "crates.io":
+		return "crates.io"
+	}
+	return "Generic"
+}
+
+// rangeType picks the OSV range type conventionally used by ecosystem. GIT
+// isn't used here: its events must be commit hashes, which this package
+// never has - only version strings - so even the "Generic" fallback uses
+// ECOSYSTEM.
+func rangeType(eco string) string {
+	switch eco {
+	case "Go":
+		return "SEMVER"
+	default:
+		return "ECOSYSTEM"
+	}
+}
+
+func pkg(cpe *wfn.Attributes) osv.Package {
+	eco := ecosystem(cpe)
+	return osv.Package{
+		Ecosystem: eco,
+		Name:      cpe.Product,
+		Purl:      purl(cpe),
+	}
+}
+
+// purl synthesizes a Package URL from a WFN. It's a best-effort rendering
+// meant to be a stable identifier, not a strictly spec-compliant purl for
+// every ecosystem (some, like Maven, need a groupId purl doesn't have here).
+func purl(cpe *wfn.Attributes) string {
+	eco := strings.ToLower(ecosystem(cpe))
+	if cpe.Vendor != "" && cpe.Vendor != wfn.Any && cpe.Vendor != wfn.NA {
+		return fmt.Sprintf("pkg:%s/%s/%s", eco, cpe.Vendor, cpe.Product)
+	}
+	return fmt.Sprintf("pkg:%s/%s", eco, cpe.Product)
+}
+
+// rangeOf translates a CPERange's VersionStart*/VersionEnd* bounds into an
+// OSV Range. OSV events have no exclusive-start notion, so an exclusive
+// start is approximated as inclusive - slightly more permissive than the
+// source data, but the closest fit OSV's schema allows.
+func rangeOf(r nvdcommon.CPERange) osv.Range {
+	eco := ecosystem(r.CPE)
+	rng := osv.Range{Type: rangeType(eco)}
+
+	introduced := r.VersionStartIncluding
+	if introduced == "" {
+		introduced = r.VersionStartExcluding
+	}
+	if introduced == "" {
+		introduced = "0"
+	}
+	rng.Events = append(rng.Events, osv.Event{Introduced: introduced})
+
+	switch {
+	case r.VersionEndExcluding != "":
+		rng.Events = append(rng.Events, osv.Event{Fixed: r.VersionEndExcluding})
+	case r.VersionEndIncluding != "":
+		rng.Events = append(rng.Events, osv.Event{LastAffected: r.VersionEndIncluding})
+	}
+	return rng
+}
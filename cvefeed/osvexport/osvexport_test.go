@@ -0,0 +1,177 @@
+// Copyright (c) Facebook, Inc. and its affiliates.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package osvexport
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/nvdtools/cvefeed/nvdcommon"
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+// fakeItem and fakeNode are minimal nvdcommon.CVEItem/LogicalTest
+// implementations, just enough to drive Convert without needing a real feed
+// adapter.
+type fakeItem struct {
+	id      string
+	details string
+	config  []nvdcommon.LogicalTest
+	metrics []nvdcommon.CVSSMetric
+}
+
+func (f *fakeItem) CVEID() string                    { return f.id }
+func (f *fakeItem) Config() []nvdcommon.LogicalTest  { return f.config }
+func (f *fakeItem) ProblemTypes() []string           { return nil }
+func (f *fakeItem) Description() string              { return f.details }
+func (f *fakeItem) CVSS20base() float64              { return 0 }
+func (f *fakeItem) CVSS30base() float64              { return 0 }
+func (f *fakeItem) CVSSMetrics() []nvdcommon.CVSSMetric {
+	return f.metrics
+}
+func (f *fakeItem) PrimaryCVSS() (nvdcommon.CVSSMetric, bool) {
+	if len(f.metrics) == 0 {
+		return nvdcommon.CVSSMetric{}, false
+	}
+	return f.metrics[0], true
+}
+
+type fakeNode struct {
+	op       string
+	negate   bool
+	children []nvdcommon.LogicalTest
+	ranges   []nvdcommon.CPERange
+}
+
+func (n *fakeNode) LogicalOperator() string { return n.op }
+func (n *fakeNode) NegateIfNeeded(b bool) bool {
+	if n.negate {
+		return !b
+	}
+	return b
+}
+func (n *fakeNode) InnerTests() []nvdcommon.LogicalTest { return n.children }
+func (n *fakeNode) CPEs() []*wfn.Attributes {
+	cpes := make([]*wfn.Attributes, len(n.ranges))
+	for i, r := range n.ranges {
+		cpes[i] = r.CPE
+	}
+	return cpes
+}
+func (n *fakeNode) MatchPlatform(*wfn.Attributes, bool) bool { return false }
+func (n *fakeNode) CPERanges() []nvdcommon.CPERange          { return n.ranges }
+
+func cpe(product string) *wfn.Attributes {
+	return &wfn.Attributes{Part: "a", Vendor: "acme", Product: product, Version: wfn.Any}
+}
+
+func TestConvertORProducesOneAffectedPerBranch(t *testing.T) {
+	item := &fakeItem{
+		id: "CVE-2026-0001",
+		config: []nvdcommon.LogicalTest{
+			&fakeNode{op: "OR", ranges: []nvdcommon.CPERange{
+				{CPE: cpe("widget"), VersionEndExcluding: "2.0.0"},
+				{CPE: cpe("gadget"), VersionEndIncluding: "1.5.0"},
+			}},
+		},
+	}
+
+	v := Convert(item)
+	if len(v.Affected) != 2 {
+		t.Fatalf("Affected has %d entries, want 2", len(v.Affected))
+	}
+	for _, a := range v.Affected {
+		if len(a.EcosystemSpecific) != 0 {
+			t.Errorf("%s: and_conditions = %v, want none for an OR branch", a.Package.Name, a.EcosystemSpecific)
+		}
+	}
+}
+
+func TestConvertANDRecordsOtherCPEsAsAndConditions(t *testing.T) {
+	item := &fakeItem{
+		id: "CVE-2026-0002",
+		config: []nvdcommon.LogicalTest{
+			&fakeNode{op: "AND", children: []nvdcommon.LogicalTest{
+				&fakeNode{op: "OR", ranges: []nvdcommon.CPERange{{CPE: cpe("widget")}}},
+				&fakeNode{op: "OR", ranges: []nvdcommon.CPERange{{CPE: cpe("gadget")}}},
+			}},
+		},
+	}
+
+	v := Convert(item)
+	if len(v.Affected) != 2 {
+		t.Fatalf("Affected has %d entries, want 2", len(v.Affected))
+	}
+	for _, a := range v.Affected {
+		others, _ := a.EcosystemSpecific["and_conditions"].([]string)
+		if len(others) != 1 {
+			t.Errorf("%s: and_conditions = %v, want the other CPE's purl", a.Package.Name, a.EcosystemSpecific)
+		}
+	}
+}
+
+func TestConvertDropsNegatedNodes(t *testing.T) {
+	item := &fakeItem{
+		id: "CVE-2026-0003",
+		config: []nvdcommon.LogicalTest{
+			&fakeNode{op: "OR", negate: true, ranges: []nvdcommon.CPERange{{CPE: cpe("widget")}}},
+		},
+	}
+
+	v := Convert(item)
+	if len(v.Affected) != 0 {
+		t.Errorf("Affected = %v, want none for a negated node", v.Affected)
+	}
+}
+
+func TestRangeOfUsesEcosystemRangeType(t *testing.T) {
+	goRange := rangeOf(nvdcommon.CPERange{
+		CPE:                 &wfn.Attributes{Part: "a", Product: "mypkg", TargetSW: "golang"},
+		VersionEndExcluding: "1.2.3",
+	})
+	if goRange.Type != "SEMVER" {
+		t.Errorf("Go range Type = %q, want SEMVER", goRange.Type)
+	}
+
+	genericRange := rangeOf(nvdcommon.CPERange{
+		CPE:                 &wfn.Attributes{Part: "a", Product: "thing"},
+		VersionEndExcluding: "1.2.3",
+	})
+	if genericRange.Type != "ECOSYSTEM" {
+		t.Errorf("generic range Type = %q, want ECOSYSTEM, not the schema-invalid GIT", genericRange.Type)
+	}
+}
+
+func TestRangeOfDefaultsIntroducedToZero(t *testing.T) {
+	r := rangeOf(nvdcommon.CPERange{
+		CPE:                 cpe("widget"),
+		VersionEndExcluding: "2.0.0",
+	})
+	if len(r.Events) != 2 || r.Events[0].Introduced != "0" {
+		t.Fatalf("Events = %+v, want an Introduced:\"0\" event first", r.Events)
+	}
+	if r.Events[1].Fixed != "2.0.0" {
+		t.Errorf("Events[1] = %+v, want Fixed:\"2.0.0\"", r.Events[1])
+	}
+}
+
+func TestPurlUsesVendorWhenPresent(t *testing.T) {
+	if got := purl(cpe("widget")); got != "pkg:generic/acme/widget" {
+		t.Errorf("purl() = %q, want pkg:generic/acme/widget", got)
+	}
+	noVendor := &wfn.Attributes{Part: "a", Product: "widget", Vendor: wfn.Any}
+	if got := purl(noVendor); got != "pkg:generic/widget" {
+		t.Errorf("purl() = %q, want pkg:generic/widget", got)
+	}
+}